@@ -0,0 +1,55 @@
+package env
+
+import (
+	"strings"
+	"sync"
+)
+
+// ResolverCache memoizes resolved DetailedMaps by their ordered pattern
+// list (see CacheKey), so that packages in a monorepo that declare an identical `env` (or
+// `globalEnv`) only pay the cost of matching it against the environment
+// once. It is safe for concurrent use by the parallel task runner.
+type ResolverCache struct {
+	mu      sync.RWMutex
+	entries map[string]DetailedMap
+}
+
+// NewResolverCache returns an empty, ready-to-use ResolverCache.
+func NewResolverCache() *ResolverCache {
+	return &ResolverCache{entries: map[string]DetailedMap{}}
+}
+
+// CacheKey builds a stable key for a set of env patterns, suitable for
+// ResolverCache. Pattern order is preserved rather than normalized away:
+// matching is order-sensitive (a later `!EXCLUDE` can undo an earlier
+// wildcard include, or vice versa), so two packages declaring the same
+// patterns in a different order can resolve to different results and must
+// not collide on the same cache entry.
+func CacheKey(patterns []string) string {
+	return strings.Join(patterns, "\x00")
+}
+
+// GetOrCompute returns the cached DetailedMap for key if present, otherwise
+// calls compute, caches, and returns its result. Concurrent calls for
+// different keys proceed in parallel; concurrent calls for the same key
+// may both invoke compute, with the last write winning, trading a rare
+// duplicated computation for a simpler, non-blocking cache.
+func (c *ResolverCache) GetOrCompute(key string, compute func() (DetailedMap, error)) (DetailedMap, error) {
+	c.mu.RLock()
+	cached, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	computed, err := compute()
+	if err != nil {
+		return DetailedMap{}, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = computed
+	c.mu.Unlock()
+
+	return computed, nil
+}
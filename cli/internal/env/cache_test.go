@@ -0,0 +1,212 @@
+package env
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestResolverCacheReusesComputedValue(t *testing.T) {
+	cache := NewResolverCache()
+	var computeCalls int32
+
+	compute := func() (DetailedMap, error) {
+		atomic.AddInt32(&computeCalls, 1)
+		return DetailedMap{All: EnvironmentVariableMap{"FOO": "bar"}}, nil
+	}
+
+	key := CacheKey([]string{"NEXT_PUBLIC_*", "NODE_ENV"})
+	for i := 0; i < 5; i++ {
+		got, err := cache.GetOrCompute(key, compute)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.All["FOO"] != "bar" {
+			t.Fatalf("got %v", got)
+		}
+	}
+
+	if calls := atomic.LoadInt32(&computeCalls); calls != 1 {
+		t.Errorf("compute was called %d times, want 1", calls)
+	}
+}
+
+func TestResolverCacheDistinctKeys(t *testing.T) {
+	cache := NewResolverCache()
+	a, err := cache.GetOrCompute(CacheKey([]string{"FOO*"}), func() (DetailedMap, error) {
+		return DetailedMap{All: EnvironmentVariableMap{"FOO": "a"}}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := cache.GetOrCompute(CacheKey([]string{"BAR*"}), func() (DetailedMap, error) {
+		return DetailedMap{All: EnvironmentVariableMap{"FOO": "b"}}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.All["FOO"] == b.All["FOO"] {
+		t.Errorf("expected distinct pattern sets to produce distinct entries")
+	}
+}
+
+func TestCacheKeyPreservesOrder(t *testing.T) {
+	// Matching is order-sensitive (a later "!EXCLUDE" can undo an earlier
+	// wildcard include, or vice versa), so two orderings of the same
+	// pattern set must NOT collide on the same key.
+	if CacheKey([]string{"FOO*", "NODE_ENV"}) != CacheKey([]string{"FOO*", "NODE_ENV"}) {
+		t.Error("expected CacheKey to be stable for the same ordering")
+	}
+	if CacheKey([]string{"FOO*", "!FOO_SECRET"}) == CacheKey([]string{"!FOO_SECRET", "FOO*"}) {
+		t.Error("expected CacheKey to differ for different pattern orderings")
+	}
+}
+
+func TestResolverCacheDoesNotCollideAcrossPatternOrderings(t *testing.T) {
+	envAtExecutionStart := EnvironmentVariableMap{
+		"FOO_SECRET": "s3cr3t",
+		"FOO_BAR":    "ok",
+	}
+
+	includeThenExclude := []string{"FOO*", "!FOO_SECRET"}
+	excludeThenInclude := []string{"!FOO_SECRET", "FOO*"}
+
+	// matchEnvPatterns resolves these two orderings differently.
+	wantIncludeThenExclude := matchEnvPatterns(includeThenExclude, envAtExecutionStart)
+	wantExcludeThenInclude := matchEnvPatterns(excludeThenInclude, envAtExecutionStart)
+	if _, ok := wantIncludeThenExclude["FOO_SECRET"]; ok {
+		t.Fatal("test fixture invalid: expected FOO_SECRET to be excluded in this ordering")
+	}
+	if _, ok := wantExcludeThenInclude["FOO_SECRET"]; !ok {
+		t.Fatal("test fixture invalid: expected FOO_SECRET to be included in this ordering")
+	}
+
+	cache := NewResolverCache()
+	gotIncludeThenExclude, err := cache.GetOrCompute(CacheKey(includeThenExclude), func() (DetailedMap, error) {
+		return DetailedMap{All: matchEnvPatterns(includeThenExclude, envAtExecutionStart)}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gotExcludeThenInclude, err := cache.GetOrCompute(CacheKey(excludeThenInclude), func() (DetailedMap, error) {
+		return DetailedMap{All: matchEnvPatterns(excludeThenInclude, envAtExecutionStart)}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := gotIncludeThenExclude.All["FOO_SECRET"]; ok {
+		t.Error("cache served the excludeThenInclude result for includeThenExclude's key")
+	}
+	if _, ok := gotExcludeThenInclude.All["FOO_SECRET"]; !ok {
+		t.Error("cache served the includeThenExclude result for excludeThenInclude's key")
+	}
+}
+
+func TestResolverCacheConcurrentUse(t *testing.T) {
+	cache := NewResolverCache()
+	key := CacheKey([]string{"NEXT_PUBLIC_*"})
+	compute := func() (DetailedMap, error) {
+		return DetailedMap{All: EnvironmentVariableMap{"NEXT_PUBLIC_API": "https://api.example.com"}}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.GetOrCompute(key, compute); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// matchEnvPatterns does the same shape of work as the real inclusion /
+// exclusion glob matching getGlobalHashableEnvVars performs against
+// envAtExecutionStart: every pattern is checked against every var, with a
+// leading "!" excluding an otherwise-matched var. This stands in for the
+// FFI-backed matcher so the benchmark below exercises comparable cost
+// rather than a trivial map copy.
+func matchEnvPatterns(patterns []string, envAtExecutionStart EnvironmentVariableMap) EnvironmentVariableMap {
+	matched := EnvironmentVariableMap{}
+	for _, pattern := range patterns {
+		exclude := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+		prefix := strings.TrimSuffix(pattern, "*")
+		isWildcard := strings.HasSuffix(pattern, "*")
+
+		for name, value := range envAtExecutionStart {
+			hit := false
+			if isWildcard {
+				hit = strings.HasPrefix(name, prefix)
+			} else {
+				hit = name == pattern
+			}
+			if !hit {
+				continue
+			}
+			if exclude {
+				delete(matched, name)
+			} else {
+				matched[name] = value
+			}
+		}
+	}
+	return matched
+}
+
+// envWithManyVars simulates the scale of a large monorepo's shell
+// environment: a handful of vars the patterns actually care about, plus
+// many unrelated ones every pattern still has to be checked against.
+func envWithManyVars(n int) EnvironmentVariableMap {
+	envAtExecutionStart := EnvironmentVariableMap{
+		"NEXT_PUBLIC_API": "https://api.example.com",
+		"NODE_ENV":        "production",
+	}
+	for i := 0; i < n; i++ {
+		envAtExecutionStart[fmt.Sprintf("UNRELATED_VAR_%d", i)] = "value"
+	}
+	return envAtExecutionStart
+}
+
+// BenchmarkSharedPatternMatching models the scenario from the request:
+// hundreds of packages in a monorepo all declaring the identical
+// env: ["NEXT_PUBLIC_*", "NODE_ENV"] pattern set against a large shell
+// environment. "uncached" re-runs matchEnvPatterns once per package, the
+// way the per-package resolution path did before ResolverCache existed.
+// "cached" resolves it once and reuses the result for every package.
+func BenchmarkSharedPatternMatching(b *testing.B) {
+	envAtExecutionStart := envWithManyVars(500)
+	patterns := []string{"NEXT_PUBLIC_*", "NODE_ENV"}
+	const packagesSharingPatterns = 200
+
+	compute := func() (DetailedMap, error) {
+		return DetailedMap{All: matchEnvPatterns(patterns, envAtExecutionStart)}, nil
+	}
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for p := 0; p < packagesSharingPatterns; p++ {
+				if _, err := compute(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			cache := NewResolverCache()
+			key := CacheKey(patterns)
+			for p := 0; p < packagesSharingPatterns; p++ {
+				if _, err := cache.GetOrCompute(key, compute); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
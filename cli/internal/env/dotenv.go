@@ -0,0 +1,78 @@
+package env
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadDotEnvFile parses a dotenv-style file (optionally KEY="value", with
+// `export` prefixes, `#` comments, and `${...}` expansion against
+// previously-loaded variables in the same file) and returns the resulting
+// EnvironmentVariableMap.
+func ReadDotEnvFile(path string) (EnvironmentVariableMap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open env file %v: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	result := EnvironmentVariableMap{}
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%v:%d: malformed line %q, expected KEY=VALUE", path, lineNum, line)
+		}
+		key = strings.TrimSpace(key)
+		value, singleQuoted := unquote(strings.TrimSpace(value))
+
+		if singleQuoted {
+			result[key] = value
+			continue
+		}
+
+		expanded, err := interpolate(value, result, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%v:%d: %w", path, lineNum, err)
+		}
+		result[key] = expanded
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read env file %v: %w", path, err)
+	}
+	return result, nil
+}
+
+func readFileTrimmed(path string) (string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(contents), "\r\n"), nil
+}
+
+// unquote strips a matching pair of surrounding quotes from value, and
+// reports whether they were single quotes: single-quoted dotenv values are
+// taken literally, with no `${...}`/`$VAR` expansion applied, while
+// double-quoted and unquoted values are expanded.
+func unquote(value string) (string, bool) {
+	if len(value) >= 2 {
+		if value[0] == '"' && value[len(value)-1] == '"' {
+			return value[1 : len(value)-1], false
+		}
+		if value[0] == '\'' && value[len(value)-1] == '\'' {
+			return value[1 : len(value)-1], true
+		}
+	}
+	return value, false
+}
@@ -0,0 +1,62 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadDotEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	contents := `# a comment
+export FOO=bar
+BAZ="quoted value"
+REF=${FOO}_suffix
+`
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	got, err := ReadDotEnvFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := EnvironmentVariableMap{
+		"FOO": "bar",
+		"BAZ": "quoted value",
+		"REF": "bar_suffix",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("got %v, want %v", got, expected)
+	}
+	for k, v := range expected {
+		if got[k] != v {
+			t.Errorf("key %q: got %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestReadDotEnvFileSingleQuotedIsLiteral(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	contents := "FOO=bar\nLITERAL='${FOO}_raw'\n"
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	got, err := ReadDotEnvFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got["LITERAL"] != "${FOO}_raw" {
+		t.Errorf("single-quoted value should not be expanded: got %q, want %q", got["LITERAL"], "${FOO}_raw")
+	}
+}
+
+func TestReadDotEnvFileMissing(t *testing.T) {
+	if _, err := ReadDotEnvFile(filepath.Join(t.TempDir(), "nope.env")); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
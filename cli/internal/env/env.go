@@ -0,0 +1,44 @@
+// Package env provides the shared types used to describe environment
+// variables that participate in turbo's task hashing.
+package env
+
+// EnvironmentVariableMap is a map of env variable names to values
+type EnvironmentVariableMap map[string]string
+
+// BySource groups EnvironmentVariableMaps by the mechanism that produced them.
+type BySource struct {
+	Explicit EnvironmentVariableMap
+	Matching EnvironmentVariableMap
+	// File holds vars declared in referenced dotenv-style files (e.g. via
+	// globalEnvFiles / envFiles), keyed the same as Explicit and Matching.
+	File EnvironmentVariableMap
+	// Secret holds vars declared via globalSecretEnv / secretEnv. Their
+	// values contribute to the hash like any other source, but must be
+	// rendered with EnvironmentVariableMap.Redacted wherever they're surfaced
+	// to the user.
+	Secret EnvironmentVariableMap
+	// Override holds vars supplied via `--var` / `--var-file` on the command
+	// line. These take precedence over every other source and force cache
+	// invalidation even when the underlying shell env is unchanged.
+	Override EnvironmentVariableMap
+}
+
+// DetailedMap is a map of env vars to their values plus a breakdown of that
+// map into the sources that contributed to it.
+type DetailedMap struct {
+	All      EnvironmentVariableMap
+	BySource BySource
+	// Files lists the paths of any env files that were loaded into BySource.File,
+	// so that callers can fold them into the global hash: a change to the file
+	// itself must invalidate the cache even if the vars it declares are unchanged.
+	Files []string
+}
+
+// Names returns a slice of the names of the env vars in this map
+func (em EnvironmentVariableMap) Names() []string {
+	names := make([]string, 0, len(em))
+	for name := range em {
+		names = append(names, name)
+	}
+	return names
+}
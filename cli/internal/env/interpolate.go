@@ -0,0 +1,71 @@
+package env
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// interpolationRef matches ${VAR}, ${VAR:-default}, and bare $VAR references.
+var interpolationRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// ResolveTemplates expands ${VAR}, $VAR, and ${VAR:-default} references in
+// each of the given patterns against envAtExecutionStart, so that globalEnv
+// entries like "${CI_PREFIX}_*" are resolved to a concrete pattern before
+// being matched against the environment. It returns an error if a
+// referenced variable is unset and has no default, or if resolving a
+// variable would require resolving itself (a cycle).
+func ResolveTemplates(patterns []string, envAtExecutionStart EnvironmentVariableMap) ([]string, error) {
+	resolved := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		expanded, err := interpolate(pattern, envAtExecutionStart, nil)
+		if err != nil {
+			return nil, err
+		}
+		resolved[i] = expanded
+	}
+	return resolved, nil
+}
+
+func interpolate(value string, env EnvironmentVariableMap, stack []string) (string, error) {
+	var rangeErr error
+	expanded := interpolationRef.ReplaceAllStringFunc(value, func(match string) string {
+		if rangeErr != nil {
+			return match
+		}
+		groups := interpolationRef.FindStringSubmatch(match)
+		name := groups[1]
+		hasDefault := groups[2] != ""
+		defaultValue := groups[3]
+		if name == "" {
+			name = groups[4]
+		}
+
+		for _, seen := range stack {
+			if seen == name {
+				rangeErr = fmt.Errorf("cycle detected while interpolating env var %q: %s -> %s", name, strings.Join(stack, " -> "), name)
+				return match
+			}
+		}
+
+		rawValue, ok := env[name]
+		if !ok {
+			if hasDefault {
+				return defaultValue
+			}
+			rangeErr = fmt.Errorf("env var %q referenced in %q is not set and has no default", name, value)
+			return match
+		}
+
+		nested, err := interpolate(rawValue, env, append(stack, name))
+		if err != nil {
+			rangeErr = err
+			return match
+		}
+		return nested
+	})
+	if rangeErr != nil {
+		return "", rangeErr
+	}
+	return expanded, nil
+}
@@ -0,0 +1,68 @@
+package env
+
+import "testing"
+
+func TestResolveTemplates(t *testing.T) {
+	testCases := []struct {
+		name     string
+		patterns []string
+		env      EnvironmentVariableMap
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "simple braced reference",
+			patterns: []string{"${CI_PREFIX}_*"},
+			env:      EnvironmentVariableMap{"CI_PREFIX": "VERCEL"},
+			expected: []string{"VERCEL_*"},
+		},
+		{
+			name:     "bare reference",
+			patterns: []string{"$PREFIX_STATIC"},
+			env:      EnvironmentVariableMap{"PREFIX_STATIC": "FOO"},
+			expected: []string{"FOO"},
+		},
+		{
+			name:     "default value used when unset",
+			patterns: []string{"${MISSING:-fallback}_*"},
+			env:      EnvironmentVariableMap{},
+			expected: []string{"fallback_*"},
+		},
+		{
+			name:     "unset without default errors",
+			patterns: []string{"${MISSING}_*"},
+			env:      EnvironmentVariableMap{},
+			wantErr:  true,
+		},
+		{
+			name:     "cycle errors",
+			patterns: []string{"${A}"},
+			env:      EnvironmentVariableMap{"A": "${B}", "B": "${A}"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ResolveTemplates(tc.patterns, tc.env)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tc.expected) {
+				t.Fatalf("got %v, want %v", got, tc.expected)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Errorf("got %q, want %q", got[i], tc.expected[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ParseVarOverride parses a single `--var NAME=VALUE` flag value.
+func ParseVarOverride(raw string) (string, string, error) {
+	key, value, ok := strings.Cut(raw, "=")
+	if !ok {
+		return "", "", fmt.Errorf("malformed --var %q, expected NAME=VALUE", raw)
+	}
+	return strings.TrimSpace(key), value, nil
+}
+
+// ReadVarFile parses a `--var-file` in either flat KEY=VALUE form (one
+// assignment per line) or as a JSON object mapping names to values. It does
+// not parse general YAML: a JSON object is valid YAML, but a flat mapping
+// written as `KEY: value` is not accepted.
+func ReadVarFile(path string) (EnvironmentVariableMap, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open var file %v: %w", path, err)
+	}
+
+	trimmed := strings.TrimSpace(string(contents))
+	if strings.HasPrefix(trimmed, "{") {
+		var obj map[string]string
+		if err := json.Unmarshal([]byte(trimmed), &obj); err != nil {
+			return nil, fmt.Errorf("%v: invalid JSON object: %w", path, err)
+		}
+		return EnvironmentVariableMap(obj), nil
+	}
+
+	result := EnvironmentVariableMap{}
+	for i, line := range strings.Split(trimmed, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%v:%d: malformed line %q, expected KEY=VALUE", path, i+1, line)
+		}
+		result[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return result, nil
+}
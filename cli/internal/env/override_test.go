@@ -0,0 +1,51 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseVarOverride(t *testing.T) {
+	key, value, err := ParseVarOverride("NODE_ENV=production")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key != "NODE_ENV" || value != "production" {
+		t.Errorf("got (%q, %q), want (%q, %q)", key, value, "NODE_ENV", "production")
+	}
+
+	if _, _, err := ParseVarOverride("malformed"); err == nil {
+		t.Fatal("expected an error for a flag without '='")
+	}
+}
+
+func TestReadVarFileKeyValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vars.txt")
+	if err := os.WriteFile(path, []byte("NODE_ENV=production\nNEXT_PUBLIC_API=https://api.example.com\n"), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	got, err := ReadVarFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["NODE_ENV"] != "production" || got["NEXT_PUBLIC_API"] != "https://api.example.com" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestReadVarFileJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vars.json")
+	if err := os.WriteFile(path, []byte(`{"NODE_ENV": "production"}`), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	got, err := ReadVarFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["NODE_ENV"] != "production" {
+		t.Errorf("got %v", got)
+	}
+}
@@ -0,0 +1,104 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// SecretSource describes where the value of a secret env var comes from.
+// Exactly one field should be set.
+type SecretSource struct {
+	Env     string   `json:"env,omitempty"`
+	File    string   `json:"file,omitempty"`
+	Command []string `json:"command,omitempty"`
+}
+
+// SecretVar is a declared `globalSecretEnv` / `secretEnv` entry: a name whose
+// value is resolved from From, and whose value must never be printed in
+// plain text (dry-run output, summaries, task logs).
+type SecretVar struct {
+	Name string       `json:"name"`
+	From SecretSource `json:"from"`
+}
+
+// RedactedValue is printed in place of a secret's real value anywhere it
+// would otherwise be surfaced to the user.
+const RedactedValue = "***"
+
+// ResolveSecretEnvVars resolves the declared secret vars using their
+// external resolver (an env var lookup, a file read, or a command's stdout),
+// and returns the resulting EnvironmentVariableMap. Resolved values
+// contribute to the hash like any other var, but should only ever be
+// rendered via Redacted.
+func ResolveSecretEnvVars(secrets []SecretVar, envAtExecutionStart EnvironmentVariableMap) (EnvironmentVariableMap, error) {
+	resolved := EnvironmentVariableMap{}
+	for _, secret := range secrets {
+		value, err := resolveSecretSource(secret.From, envAtExecutionStart)
+		if err != nil {
+			return nil, fmt.Errorf("resolving secret env var %q: %w", secret.Name, err)
+		}
+		resolved[secret.Name] = value
+	}
+	return resolved, nil
+}
+
+func resolveSecretSource(from SecretSource, envAtExecutionStart EnvironmentVariableMap) (string, error) {
+	switch {
+	case from.Env != "":
+		value, ok := envAtExecutionStart[from.Env]
+		if !ok {
+			return "", fmt.Errorf("env var %q is not set", from.Env)
+		}
+		return value, nil
+	case from.File != "":
+		path, err := expandHome(from.File)
+		if err != nil {
+			return "", fmt.Errorf("resolving %v: %w", from.File, err)
+		}
+		contents, err := readFileTrimmed(path)
+		if err != nil {
+			return "", fmt.Errorf("reading %v: %w", from.File, err)
+		}
+		return contents, nil
+	case len(from.Command) > 0:
+		// #nosec G204 -- the command is explicitly declared by the user in turbo.json
+		cmd := exec.Command(from.Command[0], from.Command[1:]...)
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("running %v: %w", strings.Join(from.Command, " "), err)
+		}
+		return strings.TrimRight(string(out), "\r\n"), nil
+	default:
+		return "", fmt.Errorf("secret has no resolvable source (expected one of env, file, command)")
+	}
+}
+
+// expandHome expands a leading "~" or "~/..." in path to the current user's
+// home directory, matching shell tilde-expansion for the common case. A
+// "~user/..." form (expansion into another user's home) is not supported.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if path == "~" {
+		return home, nil
+	}
+	return filepath.Join(home, path[2:]), nil
+}
+
+// Redacted returns a copy of em with every value replaced by RedactedValue,
+// suitable for --dry-run, --summarize, and task log output.
+func (em EnvironmentVariableMap) Redacted() EnvironmentVariableMap {
+	redacted := make(EnvironmentVariableMap, len(em))
+	for k := range em {
+		redacted[k] = RedactedValue
+	}
+	return redacted
+}
@@ -0,0 +1,98 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveSecretEnvVarsFromEnv(t *testing.T) {
+	resolved, err := ResolveSecretEnvVars(
+		[]SecretVar{{Name: "DB_PASSWORD", From: SecretSource{Env: "DB_PASS"}}},
+		EnvironmentVariableMap{"DB_PASS": "hunter2"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved["DB_PASSWORD"] != "hunter2" {
+		t.Errorf("got %q, want %q", resolved["DB_PASSWORD"], "hunter2")
+	}
+}
+
+func TestResolveSecretEnvVarsFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db")
+	if err := os.WriteFile(path, []byte("hunter2\n"), 0600); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	resolved, err := ResolveSecretEnvVars(
+		[]SecretVar{{Name: "DB_PASSWORD", From: SecretSource{File: path}}},
+		EnvironmentVariableMap{},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved["DB_PASSWORD"] != "hunter2" {
+		t.Errorf("got %q, want %q", resolved["DB_PASSWORD"], "hunter2")
+	}
+}
+
+func TestResolveSecretEnvVarsFromFileExpandsHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".secrets"), 0700); err != nil {
+		t.Fatalf("failed to create fixture dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".secrets", "db"), []byte("hunter2\n"), 0600); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	resolved, err := ResolveSecretEnvVars(
+		[]SecretVar{{Name: "DB_PASSWORD", From: SecretSource{File: "~/.secrets/db"}}},
+		EnvironmentVariableMap{},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved["DB_PASSWORD"] != "hunter2" {
+		t.Errorf("got %q, want %q", resolved["DB_PASSWORD"], "hunter2")
+	}
+}
+
+func TestResolveSecretEnvVarsFromCommand(t *testing.T) {
+	resolved, err := ResolveSecretEnvVars(
+		[]SecretVar{{Name: "DB_PASSWORD", From: SecretSource{Command: []string{"echo", "hunter2"}}}},
+		EnvironmentVariableMap{},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved["DB_PASSWORD"] != "hunter2" {
+		t.Errorf("got %q, want %q", resolved["DB_PASSWORD"], "hunter2")
+	}
+}
+
+func TestResolveSecretEnvVarsFromCommandFailure(t *testing.T) {
+	if _, err := ResolveSecretEnvVars(
+		[]SecretVar{{Name: "DB_PASSWORD", From: SecretSource{Command: []string{"false"}}}},
+		EnvironmentVariableMap{},
+	); err == nil {
+		t.Fatal("expected an error when the command exits non-zero")
+	}
+}
+
+func TestResolveSecretEnvVarsMissingEnv(t *testing.T) {
+	if _, err := ResolveSecretEnvVars(
+		[]SecretVar{{Name: "DB_PASSWORD", From: SecretSource{Env: "DB_PASS"}}},
+		EnvironmentVariableMap{},
+	); err == nil {
+		t.Fatal("expected an error when the referenced env var is unset")
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	redacted := EnvironmentVariableMap{"DB_PASSWORD": "hunter2"}.Redacted()
+	if redacted["DB_PASSWORD"] != RedactedValue {
+		t.Errorf("got %q, want %q", redacted["DB_PASSWORD"], RedactedValue)
+	}
+}
@@ -9,18 +9,96 @@ import (
 	"github.com/vercel/turbo/cli/internal/ffi"
 )
 
-// `getGlobalHashableEnvVars` calculates env var dependencies
-func getGlobalHashableEnvVars(envAtExecutionStart env.EnvironmentVariableMap, globalEnv []string) (env.DetailedMap, error) {
-	respDetailedMap, err := ffi.GetGlobalHashableEnvVars(envAtExecutionStart, globalEnv)
+// globalEnvResolverCache memoizes resolved env.DetailedMaps by their
+// ordered pattern list (see env.CacheKey). Packages across a large monorepo frequently
+// declare identical env/globalEnv patterns, so reusing the resolved result
+// avoids re-walking envAtExecutionStart and re-applying inclusion/exclusion
+// globs once per package.
+var globalEnvResolverCache = env.NewResolverCache()
+
+// `getGlobalHashableEnvVars` calculates env var dependencies. globalEnvFiles
+// is the set of paths (from `globalEnvFiles` in turbo.json, and repeatable
+// `--env-file` CLI flags) whose declared vars are folded into the result
+// alongside the vars matched from globalEnv. globalSecretEnv is the set of
+// `globalSecretEnv` declarations whose resolved values contribute to the hash
+// but are redacted wherever the result is surfaced to the user. overrides
+// is the set of vars supplied via repeatable `--var` / `--var-file` flags;
+// they take precedence over every other source.
+func getGlobalHashableEnvVars(envAtExecutionStart env.EnvironmentVariableMap, globalEnv []string, globalEnvFiles []string, globalSecretEnv []env.SecretVar, overrides env.EnvironmentVariableMap) (env.DetailedMap, error) {
+	globalEnv, err := env.ResolveTemplates(globalEnv, envAtExecutionStart)
+	if err != nil {
+		return env.DetailedMap{}, err
+	}
+
+	matched, err := globalEnvResolverCache.GetOrCompute(env.CacheKey(globalEnv), func() (env.DetailedMap, error) {
+		respDetailedMap, err := ffi.GetGlobalHashableEnvVars(envAtExecutionStart, globalEnv)
+		if err != nil {
+			return env.DetailedMap{}, err
+		}
+		return env.DetailedMap{
+			All: respDetailedMap.GetAll(),
+			BySource: env.BySource{
+				Explicit: respDetailedMap.GetBySource().GetExplicit(),
+				Matching: respDetailedMap.GetBySource().GetMatching(),
+			},
+		}, nil
+	})
 	if err != nil {
 		return env.DetailedMap{}, err
 	}
+	// mergeEnvVars is used below (rather than mutating detailedMap.All in
+	// place) so that the file/secret/override merges never write into the
+	// map cached in globalEnvResolverCache.
 	detailedMap := env.DetailedMap{
-		All: respDetailedMap.GetAll(),
+		All: matched.All,
 		BySource: env.BySource{
-			Explicit: respDetailedMap.GetBySource().GetExplicit(),
-			Matching: respDetailedMap.GetBySource().GetMatching(),
+			Explicit: matched.BySource.Explicit,
+			Matching: matched.BySource.Matching,
 		},
 	}
+
+	if len(globalEnvFiles) > 0 {
+		fileVars := env.EnvironmentVariableMap{}
+		for _, path := range globalEnvFiles {
+			loaded, err := env.ReadDotEnvFile(path)
+			if err != nil {
+				return env.DetailedMap{}, err
+			}
+			for k, v := range loaded {
+				fileVars[k] = v
+			}
+		}
+		detailedMap.All = mergeEnvVars(detailedMap.All, fileVars)
+		detailedMap.BySource.File = fileVars
+		detailedMap.Files = globalEnvFiles
+	}
+
+	if len(globalSecretEnv) > 0 {
+		secretVars, err := env.ResolveSecretEnvVars(globalSecretEnv, envAtExecutionStart)
+		if err != nil {
+			return env.DetailedMap{}, err
+		}
+		detailedMap.All = mergeEnvVars(detailedMap.All, secretVars)
+		detailedMap.BySource.Secret = secretVars
+	}
+
+	if len(overrides) > 0 {
+		detailedMap.All = mergeEnvVars(detailedMap.All, overrides)
+		detailedMap.BySource.Override = overrides
+	}
+
 	return detailedMap, nil
 }
+
+// mergeEnvVars returns a new EnvironmentVariableMap containing base with
+// overlay layered on top, without mutating either input.
+func mergeEnvVars(base, overlay env.EnvironmentVariableMap) env.EnvironmentVariableMap {
+	merged := make(env.EnvironmentVariableMap, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		merged[k] = v
+	}
+	return merged
+}
@@ -1,6 +1,8 @@
 package run
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -8,9 +10,22 @@ import (
 )
 
 func TestGetGlobalHashableEnvVars(t *testing.T) {
+	envFilePath := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envFilePath, []byte("API_KEY=abc123\n"), 0644); err != nil {
+		t.Fatalf("failed to write env file fixture: %v", err)
+	}
+
+	overriddenEnvFilePath := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(overriddenEnvFilePath, []byte("NODE_ENV=development\n"), 0644); err != nil {
+		t.Fatalf("failed to write env file fixture: %v", err)
+	}
+
 	testCases := []struct {
 		envAtExecutionStart env.EnvironmentVariableMap
 		globalEnv           []string
+		globalEnvFiles      []string
+		globalSecretEnv     []env.SecretVar
+		overrides           env.EnvironmentVariableMap
 		expectedMap         env.DetailedMap
 	}{
 		{
@@ -24,10 +39,92 @@ func TestGetGlobalHashableEnvVars(t *testing.T) {
 			},
 			expectedMap: env.DetailedMap{},
 		},
+		{
+			// "${CI_PREFIX}_ENV" interpolates against CI_PREFIX before it's
+			// matched, so it resolves to the literal (non-wildcard) name
+			// "VERCEL_ENV" and lands in BySource.Explicit.
+			envAtExecutionStart: env.EnvironmentVariableMap{
+				"CI_PREFIX":  "VERCEL",
+				"VERCEL_ENV": "production",
+			},
+			globalEnv: []string{
+				"${CI_PREFIX}_ENV",
+			},
+			expectedMap: env.DetailedMap{
+				All: env.EnvironmentVariableMap{
+					"VERCEL_ENV": "production",
+				},
+				BySource: env.BySource{
+					Explicit: env.EnvironmentVariableMap{
+						"VERCEL_ENV": "production",
+					},
+				},
+			},
+		},
+		{
+			// globalEnvFiles vars are folded into All and BySource.File even
+			// when globalEnv matches nothing on its own.
+			envAtExecutionStart: env.EnvironmentVariableMap{},
+			globalEnvFiles:      []string{envFilePath},
+			expectedMap: env.DetailedMap{
+				All: env.EnvironmentVariableMap{
+					"API_KEY": "abc123",
+				},
+				BySource: env.BySource{
+					File: env.EnvironmentVariableMap{
+						"API_KEY": "abc123",
+					},
+				},
+				Files: []string{envFilePath},
+			},
+		},
+		{
+			// globalSecretEnv resolves DB_PASSWORD from the DB_PASS shell var
+			// and lands it in BySource.Secret alongside All.
+			envAtExecutionStart: env.EnvironmentVariableMap{
+				"DB_PASS": "hunter2",
+			},
+			globalSecretEnv: []env.SecretVar{
+				{Name: "DB_PASSWORD", From: env.SecretSource{Env: "DB_PASS"}},
+			},
+			expectedMap: env.DetailedMap{
+				All: env.EnvironmentVariableMap{
+					"DB_PASSWORD": "hunter2",
+				},
+				BySource: env.BySource{
+					Secret: env.EnvironmentVariableMap{
+						"DB_PASSWORD": "hunter2",
+					},
+				},
+			},
+		},
+		{
+			// A --var override for the same key wins over the value loaded
+			// from an env file.
+			envAtExecutionStart: env.EnvironmentVariableMap{},
+			globalEnvFiles:      []string{overriddenEnvFilePath},
+			overrides: env.EnvironmentVariableMap{
+				"NODE_ENV": "production",
+			},
+			expectedMap: env.DetailedMap{
+				All: env.EnvironmentVariableMap{
+					"NODE_ENV": "production",
+				},
+				BySource: env.BySource{
+					File: env.EnvironmentVariableMap{
+						"NODE_ENV": "development",
+					},
+					Override: env.EnvironmentVariableMap{
+						"NODE_ENV": "production",
+					},
+				},
+				Files: []string{overriddenEnvFilePath},
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
-		result, err := getGlobalHashableEnvVars(testCase.envAtExecutionStart, testCase.globalEnv)
+		result, err := getGlobalHashableEnvVars(testCase.envAtExecutionStart, testCase.globalEnv, testCase.globalEnvFiles, testCase.globalSecretEnv, testCase.overrides)
 		assert.NoError(t, err)
 		assert.Equal(t, testCase.expectedMap, result)
 	}